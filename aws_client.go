@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// accountFlags holds the --source-*/--dest-* flags shared by every
+// subcommand that connects to AWS on both ends of a migration.
+type accountFlags struct {
+	sourceProfile, sourceRegion, sourceRoleARN *string
+	destProfile, destRegion, destRoleARN       *string
+}
+
+// addAccountFlags registers --source-profile/--dest-profile,
+// --source-region/--dest-region and --source-role-arn/--dest-role-arn on fs.
+// A role ARN is assumed (via stscreds.AssumeRoleProvider) on top of the
+// matching profile's credentials when set, so a CI role in one account can
+// write into another account's Parameter Store.
+func addAccountFlags(fs *flag.FlagSet) *accountFlags {
+	return &accountFlags{
+		sourceProfile: fs.String("source-profile", "", "AWS profile to read source parameters with"),
+		sourceRegion:  fs.String("source-region", "", "AWS region for the source (defaults to the profile's region)"),
+		sourceRoleARN: fs.String("source-role-arn", "", "if set, assume this role on top of --source-profile to read the source"),
+		destProfile:   fs.String("dest-profile", "", "AWS profile to write destination parameters with"),
+		destRegion:    fs.String("dest-region", "", "AWS region for the destination (defaults to the profile's region)"),
+		destRoleARN:   fs.String("dest-role-arn", "", "if set, assume this role on top of --dest-profile to write the destination"),
+	}
+}
+
+// connect resolves both the source and destination SSM clients described by
+// af, in one call so subcommands can fail fast if either side is
+// misconfigured.
+func (af *accountFlags) connect() (source, dest *ssm.Client, err error) {
+	source, err = connectToAWS(*af.sourceProfile, *af.sourceRegion, *af.sourceRoleARN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to source AWS account, %v", err)
+	}
+	dest, err = connectToAWS(*af.destProfile, *af.destRegion, *af.destRoleARN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to destination AWS account, %v", err)
+	}
+	return source, dest, nil
+}
+
+// connectToAWS builds an SSM client for one side of a migration (source or
+// destination), resolved from a local profile and, if roleARN is set,
+// assumed into a different account/role on top of that profile's
+// credentials. region overrides the profile's configured region when set.
+func connectToAWS(profile, region, roleARN string) (*ssm.Client, error) {
+	var opts []func(*config.LoadOptions) error
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.TODO(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	if roleARN != "" {
+		stsClient := sts.NewFromConfig(cfg)
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, roleARN))
+	}
+
+	return ssm.NewFromConfig(cfg), nil
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+)
+
+// getParametersMaxBatch is the largest name list ssm.GetParameters accepts
+// per call.
+const getParametersMaxBatch = 10
+
+// defaultConcurrency is used when --concurrency is unset or <= 0.
+const defaultConcurrency = 8
+
+// chunkNames splits names into slices of at most size entries. An empty
+// names yields no chunks at all, rather than one empty chunk.
+func chunkNames(names []string, size int) [][]string {
+	if len(names) == 0 {
+		return nil
+	}
+	var chunks [][]string
+	for size < len(names) {
+		names, chunks = names[size:], append(chunks, names[:size:size])
+	}
+	chunks = append(chunks, names)
+	return chunks
+}
+
+// getParametersBatch resolves many parameters at once using ssm.GetParameters
+// (chunked to its 10-name limit) instead of one GetParameter call per name,
+// fanning the chunks out across a bounded worker pool. Names with no such
+// parameter are simply absent from the result, matching GetParameters'
+// own behavior of reporting them in InvalidParameters rather than erroring.
+func getParametersBatch(ctx context.Context, client *ssm.Client, limiter *rateLimiter, concurrency int, names []string) (map[string]types.Parameter, error) {
+	chunks := chunkNames(names, getParametersMaxBatch)
+	perChunk := make([]map[string]types.Parameter, len(chunks))
+
+	errs := runConcurrentIndexed(concurrency, len(chunks), func(i int) error {
+		input := &ssm.GetParametersInput{
+			Names:          chunks[i],
+			WithDecryption: aws.Bool(true),
+		}
+		var output *ssm.GetParametersOutput
+		err := retryWithBackoff(ctx, func() error {
+			limiter.wait(ctx)
+			var callErr error
+			output, callErr = client.GetParameters(ctx, input)
+			return callErr
+		})
+		if err != nil {
+			return err
+		}
+		chunkResult := make(map[string]types.Parameter, len(output.Parameters))
+		for _, p := range output.Parameters {
+			chunkResult[aws.ToString(p.Name)] = p
+		}
+		perChunk[i] = chunkResult
+		return nil
+	})
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]types.Parameter, len(names))
+	for _, chunkResult := range perChunk {
+		for name, p := range chunkResult {
+			results[name] = p
+		}
+	}
+	return results, nil
+}
+
+// describeParametersBatch resolves descriptions for many parameters at once
+// using ssm.DescribeParameters with a chunked "Name" filter, instead of one
+// DescribeParameters call per name, fanning the chunks out across a bounded
+// worker pool.
+func describeParametersBatch(ctx context.Context, client *ssm.Client, limiter *rateLimiter, concurrency int, names []string) (map[string]string, error) {
+	chunks := chunkNames(names, getParametersMaxBatch)
+	perChunk := make([]map[string]string, len(chunks))
+
+	errs := runConcurrentIndexed(concurrency, len(chunks), func(i int) error {
+		input := &ssm.DescribeParametersInput{
+			ParameterFilters: []types.ParameterStringFilter{
+				{
+					Key:    aws.String("Name"),
+					Values: chunks[i],
+				},
+			},
+		}
+		var output *ssm.DescribeParametersOutput
+		err := retryWithBackoff(ctx, func() error {
+			limiter.wait(ctx)
+			var callErr error
+			output, callErr = client.DescribeParameters(ctx, input)
+			return callErr
+		})
+		if err != nil {
+			return err
+		}
+		chunkResult := make(map[string]string, len(output.Parameters))
+		for _, p := range output.Parameters {
+			chunkResult[aws.ToString(p.Name)] = aws.ToString(p.Description)
+		}
+		perChunk[i] = chunkResult
+		return nil
+	})
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+
+	descriptions := make(map[string]string, len(names))
+	for _, chunkResult := range perChunk {
+		for name, d := range chunkResult {
+			descriptions[name] = d
+		}
+	}
+	return descriptions, nil
+}
+
+// rateLimiter is a simple token bucket shared by every worker so a bounded
+// pool of goroutines still stays under SSM's per-account throughput limits.
+type rateLimiter struct {
+	tokens chan struct{}
+	stop   chan struct{}
+}
+
+// newRateLimiter starts a token bucket that refills at ratePerSecond.
+func newRateLimiter(ratePerSecond int) *rateLimiter {
+	if ratePerSecond <= 0 {
+		ratePerSecond = 1
+	}
+	rl := &rateLimiter{
+		tokens: make(chan struct{}, ratePerSecond),
+		stop:   make(chan struct{}),
+	}
+	ticker := time.NewTicker(time.Second / time.Duration(ratePerSecond))
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				select {
+				case rl.tokens <- struct{}{}:
+				default:
+				}
+			case <-rl.stop:
+				return
+			}
+		}
+	}()
+	return rl
+}
+
+func (rl *rateLimiter) wait(ctx context.Context) {
+	select {
+	case <-rl.tokens:
+	case <-ctx.Done():
+	}
+}
+
+func (rl *rateLimiter) close() {
+	close(rl.stop)
+}
+
+// retryWithBackoff retries fn on SSM throttling errors with exponential
+// backoff and jitter, up to 5 attempts.
+func retryWithBackoff(ctx context.Context, fn func() error) error {
+	const maxAttempts = 5
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		err = fn()
+		if err == nil || !isThrottlingError(err) {
+			return err
+		}
+		backoff := time.Duration(1<<attempt) * 100 * time.Millisecond
+		jitter := time.Duration(rand.Int63n(int64(backoff) / 2))
+		select {
+		case <-time.After(backoff + jitter):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+func isThrottlingError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ThrottlingException", "RequestLimitExceeded":
+			return true
+		}
+	}
+	return false
+}
+
+// runConcurrentIndexed runs fn(0), fn(1), ..., fn(n-1) across a bounded pool
+// of workers, collecting one error per index (nil on success).
+func runConcurrentIndexed(concurrency, n int, fn func(i int) error) []error {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+	if concurrency <= 0 {
+		return nil
+	}
+
+	errs := make([]error, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errs
+}
+
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
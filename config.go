@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MigrationRule describes how to move one set of parameters from a source
+// path template to a destination path template for one or more environments.
+//
+// Parameters can be selected explicitly via Parameters, or matched with
+// Prefix/Glob against everything under SourcePath. SourcePath/DestPath may
+// contain a "{env}" placeholder that gets substituted per entry in
+// Environments.
+type MigrationRule struct {
+	Name         string            `yaml:"name" json:"name"`
+	SourcePath   string            `yaml:"source_path" json:"source_path"`
+	DestPath     string            `yaml:"dest_path" json:"dest_path"`
+	Parameters   []string          `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Prefix       string            `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	Glob         string            `yaml:"glob,omitempty" json:"glob,omitempty"`
+	Environments []string          `yaml:"environments" json:"environments"`
+	Overwrite    bool              `yaml:"overwrite,omitempty" json:"overwrite,omitempty"`
+	Tier         string            `yaml:"tier,omitempty" json:"tier,omitempty"`
+	KMSKeyID     string            `yaml:"kms_key_id,omitempty" json:"kms_key_id,omitempty"`
+	Tags         map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// MigrationConfig is the top-level shape of a migration plan input file.
+type MigrationConfig struct {
+	Rules []MigrationRule `yaml:"rules" json:"rules"`
+}
+
+// loadMigrationConfig reads a YAML or JSON migration config from path,
+// choosing the decoder based on the file extension.
+func loadMigrationConfig(path string) (*MigrationConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration config %q: %v", path, err)
+	}
+
+	var cfg MigrationConfig
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML migration config %q: %v", path, err)
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON migration config %q: %v", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported migration config extension %q (want .yaml, .yml or .json)", ext)
+	}
+
+	if len(cfg.Rules) == 0 {
+		return nil, fmt.Errorf("migration config %q declares no rules", path)
+	}
+	for i, rule := range cfg.Rules {
+		if rule.SourcePath == "" || rule.DestPath == "" {
+			return nil, fmt.Errorf("rule %d (%s): source_path and dest_path are required", i, rule.Name)
+		}
+		if len(rule.Environments) == 0 {
+			return nil, fmt.Errorf("rule %d (%s): at least one environment is required", i, rule.Name)
+		}
+	}
+	return &cfg, nil
+}
+
+// resolvedPath substitutes the "{env}" placeholder in a rule's path
+// template with the given environment name.
+func resolvedPath(template, environment string) string {
+	return strings.ReplaceAll(template, "{env}", environment)
+}
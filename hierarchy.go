@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// MigrateOpts controls how migratePrefix walks and rewrites a parameter
+// hierarchy.
+type MigrateOpts struct {
+	// WithDecryption controls whether SecureString values are decrypted on
+	// read; it must be true for their plaintext to be re-encrypted on write.
+	WithDecryption bool
+	// DestKMSKeyID, if set, re-encrypts SecureString parameters under this
+	// key on write. When empty no KeyId is sent, so SSM uses the
+	// destination's default key; the source's KeyId is not copied.
+	DestKMSKeyID string
+	// Overwrite allows an existing destination parameter to be replaced.
+	Overwrite bool
+	// Include, if set, restricts migration to suffixes matching this regex.
+	Include *regexp.Regexp
+	// Exclude, if set, skips suffixes matching this regex.
+	Exclude *regexp.Regexp
+	// IncludeHistory replays every prior version of each parameter onto the
+	// destination via GetParameterHistory, instead of writing only its
+	// current value, so audit trails and {{resolve:ssm:.../N}} references
+	// stay valid after the migration.
+	IncludeHistory bool
+}
+
+// migratePrefix recursively copies every parameter under srcPrefix onto
+// dstPrefix, preserving the suffix path, e.g. a parameter at
+// srcPrefix+"/db/host" lands at dstPrefix+"/db/host". It replaces the
+// previous approach of hand-enumerating variable names per environment.
+// sourceClient and destClient may be the same client or, for a cross-account/
+// cross-region migration, two different ones.
+func migratePrefix(sourceClient, destClient *ssm.Client, srcPrefix, dstPrefix string, opts MigrateOpts) error {
+	srcPrefix = strings.TrimSuffix(srcPrefix, "/")
+	dstPrefix = strings.TrimSuffix(dstPrefix, "/")
+
+	input := &ssm.GetParametersByPathInput{
+		Path:           aws.String(srcPrefix),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(opts.WithDecryption),
+	}
+	paginator := ssm.NewGetParametersByPathPaginator(sourceClient, input)
+
+	var migrated, skipped int
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return fmt.Errorf("failed to list parameters under %q: %v", srcPrefix, err)
+		}
+		for _, param := range page.Parameters {
+			suffix := strings.TrimPrefix(aws.ToString(param.Name), srcPrefix)
+			if opts.Include != nil && !opts.Include.MatchString(suffix) {
+				skipped++
+				continue
+			}
+			if opts.Exclude != nil && opts.Exclude.MatchString(suffix) {
+				skipped++
+				continue
+			}
+
+			destName := dstPrefix + suffix
+			if err := migrateOne(sourceClient, destClient, param, destName, opts); err != nil {
+				return fmt.Errorf("failed to migrate %q -> %q: %v", aws.ToString(param.Name), destName, err)
+			}
+			fmt.Printf("migrated %s -> %s\n", aws.ToString(param.Name), destName)
+			migrated++
+		}
+	}
+
+	fmt.Printf("migratePrefix %s -> %s: %d migrated, %d skipped\n", srcPrefix, dstPrefix, migrated, skipped)
+	return nil
+}
+
+func migrateOne(sourceClient, destClient *ssm.Client, source types.Parameter, destName string, opts MigrateOpts) error {
+	sourceName := aws.ToString(source.Name)
+
+	if opts.IncludeHistory {
+		if err := replayHistory(sourceClient, destClient, sourceName, destName, opts.DestKMSKeyID); err != nil {
+			return err
+		}
+	}
+
+	description, err := getParameterDescription(sourceClient, sourceName)
+	if err != nil {
+		return fmt.Errorf("failed to get description: %v", err)
+	}
+	// Even under IncludeHistory, the final write always goes through
+	// putParameter so tier/allowed-pattern/policies/tags land on the
+	// destination's current version; replayHistory alone doesn't carry tags
+	// and isn't guaranteed to carry tier/policies on every historical entry.
+	overwrite := opts.Overwrite || opts.IncludeHistory
+	return putParameter(sourceClient, destClient, sourceName, destName, description, &source, overwrite, opts.DestKMSKeyID, nil)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// replayHistory reads every version of sourceName via GetParameterHistory,
+// oldest first, and writes each one onto destName in the same order. When
+// --include-history is set it runs before the usual putParameter call (which
+// then adds one more version carrying tier/policies/tags), so the
+// destination's own version numbers (and any {{resolve:ssm:name:N}}
+// references to them) line up with the source's audit trail instead of
+// collapsing to a single version 1.
+func replayHistory(sourceClient, destClient *ssm.Client, sourceName, destName, kmsKeyID string) error {
+	ctx := context.TODO()
+	var history []types.ParameterHistory
+	paginator := ssm.NewGetParameterHistoryPaginator(sourceClient, &ssm.GetParameterHistoryInput{
+		Name:           aws.String(sourceName),
+		WithDecryption: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read history for %q: %v", sourceName, err)
+		}
+		history = append(history, page.Parameters...)
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("parameter %q has no history", sourceName)
+	}
+
+	for _, version := range history {
+		input := &ssm.PutParameterInput{
+			Name:        aws.String(destName),
+			Value:       version.Value,
+			Type:        version.Type,
+			Description: version.Description,
+			Overwrite:   aws.Bool(true),
+		}
+		if version.AllowedPattern != nil {
+			input.AllowedPattern = version.AllowedPattern
+		}
+		if kmsKeyID != "" && version.Type == types.ParameterTypeSecureString {
+			input.KeyId = aws.String(kmsKeyID)
+		}
+		if _, err := destClient.PutParameter(ctx, input); err != nil {
+			return fmt.Errorf("failed to replay %q version %d onto %q: %v", sourceName, version.Version, destName, err)
+		}
+	}
+	return nil
+}
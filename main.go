@@ -2,91 +2,17 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"regexp"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
 )
 
-func getAllParameters(client *ssm.Client) ([]types.ParameterMetadata, error) {
-	var parameters []types.ParameterMetadata
-	input := &ssm.DescribeParametersInput{}
-	paginator := ssm.NewDescribeParametersPaginator(client, input)
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
-		if err != nil {
-			return nil, err
-		}
-		parameters = append(parameters, page.Parameters...)
-		break
-	}
-	return parameters, nil
-}
-
-func getParameterDetails(client *ssm.Client, name string) (*types.Parameter, error) {
-	fmt.Printf("Getting parameter details for: %v\n", name)
-	input := &ssm.GetParameterInput{
-		Name:           aws.String(name),
-		WithDecryption: aws.Bool(true),
-	}
-	result, err := client.GetParameter(context.TODO(), input)
-	if err != nil {
-		return nil, err
-	}
-	return result.Parameter, nil
-}
-
-func connectToAWSByProfile(profile string) (*ssm.Client, error) {
-	// Load the default configuration with the specified profile
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithSharedConfigProfile(profile))
-	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
-	}
-
-	client := ssm.NewFromConfig(cfg)
-	return client, nil
-}
-
-// generateOldVariableName generates a variable name in the old format /asset-accounting/{environment}/{variableName}
-func generateOldVariableName(environment, variableName string) string {
-	return fmt.Sprintf("/asset-accounting/%s/%s", environment, variableName)
-}
-
-// generateNewVariableName generates a variable name in the new format /asset-accounting/serviceplatform/{environment}/{variableName}
-func generateNewVariableName(environment, variableName string) string {
-	return fmt.Sprintf("/asset-accounting/serviceplatform/%s/%s", environment, variableName)
-}
-
-// generateVariableNameMap generates a map from old variable names to new variable names
-func generateVariableNameMap(environment string) map[string]string {
-	//serverlessParams := []string{
-	//	"REDISCLOUD_URL", "REDIS_ENABLED_TLS", "REDIS_DB", "LOG_LEVEL", "JAWSDB_URL",
-	//	"MYSQL_HOST", "MYSQL_PORT", "MYSQL_USER", "MYSQL_PASSWORD", "MYSQL_DB",
-	//	"MYSQL_MAX_OPEN_CONNS", "MYSQL_MAX_IDLE_CONNS", "MYSQL_CONN_MAX_LIFETIME",
-	//	"JAWSDB_REPLICATION_URL", "MYSQL_REPLICATION_HOST", "MYSQL_REPLICATION_PORT",
-	//	"MYSQL_REPLICATION_USER", "MYSQL_REPLICATION_PASSWORD", "MYSQL_REPLICATION_DB",
-	//	"MYSQL_REPLICATION_MAX_OPEN_CONNS", "MYSQL_REPLICATION_MAX_IDLE_CONNS",
-	//	"MYSQL_REPLICATION_CONN_MAX_LIFETIME", "DD_API_KEY", "DD_SITE", "DD_ENV",
-	//	"DD_SERVERLESS_LOGS_ENABLED", "DD_MERGE_XRAY_TRACES", "DD_TRACE_ENABLED",
-	//	"ACCPLUS_BASE_URL",
-	//}
-
-	serverlessParams := []string{
-		"REDISCLOUD_URL",
-	}
-
-	variableNameMap := make(map[string]string)
-	for _, param := range serverlessParams {
-		oldName := generateOldVariableName(environment, param)
-		newName := generateNewVariableName(environment, param)
-		variableNameMap[oldName] = newName
-	}
-	return variableNameMap
-}
-
 func getParameterDescription(client *ssm.Client, name string) (string, error) {
 	input := &ssm.DescribeParametersInput{
 		ParameterFilters: []types.ParameterStringFilter{
@@ -106,69 +32,221 @@ func getParameterDescription(client *ssm.Client, name string) (string, error) {
 	return aws.ToString(output.Parameters[0].Description), nil
 }
 
-func putParameter(client *ssm.Client, name, description string, dest *types.Parameter) error {
+// putParameter writes dest's value/type to destName on destClient, along
+// with everything about sourceName on sourceClient that a plain PutParameter
+// call would otherwise drop: tier, allowed pattern, policies and tags (the
+// latter via AddTagsToResource, since PutParameter's own Tags field only
+// takes effect on initial creation, not on an overwrite). When kmsKeyID is
+// set and dest is a SecureString, the value is re-encrypted under that key,
+// so a value can be copied into a destination account/region where the
+// source key doesn't exist. When kmsKeyID is empty no KeyId is sent, so SSM
+// encrypts under the destination's default key; the source's KeyId is not
+// copied. extraTags
+// are merged on top of the source's tags, taking precedence.
+func putParameter(sourceClient, destClient *ssm.Client, sourceName, destName, description string, dest *types.Parameter, overwrite bool, kmsKeyID string, extraTags map[string]string) error {
+	meta, err := getSourceMetadata(sourceClient, sourceName)
+	if err != nil {
+		return fmt.Errorf("failed to read source metadata for %q: %v", sourceName, err)
+	}
+
 	input := &ssm.PutParameterInput{
-		Name:        aws.String(name),
+		Name:        aws.String(destName),
 		Value:       aws.String(*dest.Value),
 		Type:        dest.Type,
 		Description: aws.String(description),
+		Overwrite:   aws.Bool(overwrite),
+		Tier:        meta.Tier,
+	}
+	if meta.AllowedPattern != "" {
+		input.AllowedPattern = aws.String(meta.AllowedPattern)
+	}
+	if meta.Policies != "" {
+		input.Policies = aws.String(meta.Policies)
+	}
+	if kmsKeyID != "" && dest.Type == types.ParameterTypeSecureString {
+		input.KeyId = aws.String(kmsKeyID)
+	}
+	if _, err := destClient.PutParameter(context.TODO(), input); err != nil {
+		return err
+	}
+
+	tags := meta.Tags
+	for k, v := range extraTags {
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		tags[k] = v
+	}
+	return tagDestination(destClient, destName, tags)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <plan|apply|rollback|migrate-prefix|sync> [flags]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  plan     resolve a migration config into a plan and print a dry-run diff\n")
+	fmt.Fprintf(os.Stderr, "  apply    execute a previously resolved plan, recording a rollback journal\n")
+	fmt.Fprintf(os.Stderr, "  rollback       undo an apply using its rollback journal\n")
+	fmt.Fprintf(os.Stderr, "  migrate-prefix recursively migrate an entire SSM hierarchy to a new prefix\n")
+	fmt.Fprintf(os.Stderr, "  sync           copy parameters between any two pkg/paramstore backends\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "plan":
+		runPlan(os.Args[2:])
+	case "apply":
+		runApply(os.Args[2:])
+	case "rollback":
+		runRollback(os.Args[2:])
+	case "migrate-prefix":
+		runMigratePrefix(os.Args[2:])
+	case "sync":
+		runSync(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
 	}
-	_, err := client.PutParameter(context.TODO(), input)
-	return err
 }
 
-func copyParameter(client *ssm.Client, sourceName, destName string) error {
-	fmt.Printf(" =====================\n")
-	sourceParam, err := getParameterDetails(client, sourceName)
+func runPlan(args []string) {
+	fs := flag.NewFlagSet("plan", flag.ExitOnError)
+	configPath := fs.String("config", "", "path to the migration config (YAML or JSON)")
+	planFile := fs.String("plan-file", "", "if set, persist the resolved plan to this path for a follow-up apply")
+	dryRun := fs.Bool("dry-run", true, "resolve and print the diff without writing anything (plan never writes)")
+	concurrency := fs.Int("concurrency", defaultConcurrency, "number of workers reading parameters concurrently")
+	account := addAccountFlags(fs)
+	fs.Parse(args)
+
+	if *configPath == "" {
+		log.Fatalf("plan requires --config")
+	}
+	_ = *dryRun // plan is always a dry run; the flag exists so --dry-run=false is a loud, explicit error below
+	if !*dryRun {
+		log.Fatalf("plan cannot write parameters; run apply against --plan-file instead")
+	}
+
+	cfg, err := loadMigrationConfig(*configPath)
 	if err != nil {
-		return fmt.Errorf("failed to get source parameter details: %v", err)
+		log.Fatalf("failed to load migration config, %v", err)
 	}
-	description, err := getParameterDescription(client, sourceName)
+	sourceClient, destClient, err := account.connect()
 	if err != nil {
-		return fmt.Errorf("failed to get source parameter description: %v", err)
+		log.Fatalf("%v", err)
 	}
-	fmt.Printf("name: %v, value: %v, type: %v, description: %v \n", *sourceParam.Name, *sourceParam.Value, *&sourceParam.Type, description)
 
-	err = putParameter(client, destName, description, sourceParam)
+	migrationPlan, err := resolvePlan(sourceClient, destClient, cfg, *concurrency)
 	if err != nil {
-		return fmt.Errorf("failed to put destination parameter: %v", err)
+		log.Fatalf("failed to resolve plan, %v", err)
+	}
+	printDiff(migrationPlan)
+
+	if *planFile != "" {
+		if err := savePlanFile(migrationPlan, *planFile); err != nil {
+			log.Fatalf("failed to save plan file, %v", err)
+		}
+		fmt.Printf("plan written to %s\n", *planFile)
 	}
-	fmt.Printf("Success copied parameter from %v to %v\n", sourceName, destName)
-	return nil
 }
 
-func main() {
-	environemnt := "staging" // or "production" or beta
-	profile := "aa_stg"
+func runApply(args []string) {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	planFile := fs.String("plan-file", "", "plan file produced by `plan --plan-file`")
+	journalFile := fs.String("journal-file", "rollback-journal.json", "where to record the rollback journal")
+	concurrency := fs.Int("concurrency", defaultConcurrency, "number of workers reading source parameters concurrently")
+	includeHistory := fs.Bool("include-history", false, "replay every prior version of each parameter instead of just its current value")
+	account := addAccountFlags(fs)
+	fs.Parse(args)
 
-	if environemnt == "production" {
-		profile = "aa_prod"
+	if *planFile == "" {
+		log.Fatalf("apply requires --plan-file")
 	}
 
-	client, err := connectToAWSByProfile(profile)
+	migrationPlan, err := loadPlanFile(*planFile)
 	if err != nil {
-		log.Fatalf("failed to connect to AWS, %v", err)
+		log.Fatalf("failed to load plan file, %v", err)
+	}
+	sourceClient, destClient, err := account.connect()
+	if err != nil {
+		log.Fatalf("%v", err)
 	}
 
-	// Example usage
-	//params, err := getAllParameters(client)
-	//if err != nil {
-	//	log.Fatalf("failed to get parameters, %v", err)
-	//}
+	if err := applyPlan(sourceClient, destClient, migrationPlan, *planFile, *journalFile, *concurrency, *includeHistory); err != nil {
+		log.Fatalf("failed to apply plan, %v", err)
+	}
+	fmt.Printf("apply complete, rollback journal written to %s\n", *journalFile)
+}
 
-	oldToNewEnvName := generateVariableNameMap(environemnt)
+func runRollback(args []string) {
+	fs := flag.NewFlagSet("rollback", flag.ExitOnError)
+	journalFile := fs.String("journal-file", "", "rollback journal produced by `apply`")
+	destProfile := fs.String("dest-profile", "", "AWS profile the apply wrote to, and that rollback restores")
+	destRegion := fs.String("dest-region", "", "AWS region the apply wrote to (defaults to the profile's region)")
+	destRoleARN := fs.String("dest-role-arn", "", "if set, assume this role on top of --dest-profile to roll back")
+	fs.Parse(args)
 
-	for oldEnvName, newEnName := range oldToNewEnvName {
-		fmt.Printf("oldName: %v == newName: %v\n", oldEnvName, newEnName)
-		// details, err := getParameterDetails(client, oldEnvName)
-		// if err != nil {
-		// 	log.Fatalf("failed to get parameter details, %v", err)
-		// }
-		// fmt.Printf("name: %v, value: %v, type: %v, description: %v \n", *details.Name, *details.Value, *&details.Type)
+	if *journalFile == "" {
+		log.Fatalf("rollback requires --journal-file")
+	}
+
+	journal := mustLoadJournalForRollback(*journalFile)
+	destClient, err := connectToAWS(*destProfile, *destRegion, *destRoleARN)
+	if err != nil {
+		log.Fatalf("failed to connect to AWS, %v", err)
+	}
+
+	if err := rollbackJournal(destClient, journal); err != nil {
+		log.Fatalf("failed to roll back, %v", err)
+	}
+	fmt.Println("rollback complete")
+}
 
-		err = copyParameter(client, oldEnvName, newEnName)
+func runMigratePrefix(args []string) {
+	fs := flag.NewFlagSet("migrate-prefix", flag.ExitOnError)
+	sourcePrefix := fs.String("source-prefix", "", "source parameter path, e.g. /asset-accounting/staging")
+	destPrefix := fs.String("dest-prefix", "", "destination parameter path, e.g. /asset-accounting/serviceplatform/staging")
+	overwrite := fs.Bool("overwrite", false, "overwrite existing destination parameters")
+	kmsKeyID := fs.String("dest-kms-key-id", "", "if set, re-encrypt SecureString parameters under this destination KMS key")
+	include := fs.String("include", "", "if set, only migrate suffixes matching this regex")
+	exclude := fs.String("exclude", "", "if set, skip suffixes matching this regex")
+	includeHistory := fs.Bool("include-history", false, "replay every prior version of each parameter instead of just its current value")
+	account := addAccountFlags(fs)
+	fs.Parse(args)
+
+	if *sourcePrefix == "" || *destPrefix == "" {
+		log.Fatalf("migrate-prefix requires --source-prefix and --dest-prefix")
+	}
+
+	opts := MigrateOpts{
+		WithDecryption: true,
+		DestKMSKeyID:   *kmsKeyID,
+		Overwrite:      *overwrite,
+		IncludeHistory: *includeHistory,
+	}
+	if *include != "" {
+		re, err := regexp.Compile(*include)
 		if err != nil {
-			log.Fatalf("failed to copy parameter, %v", err)
+			log.Fatalf("invalid --include regex, %v", err)
 		}
+		opts.Include = re
+	}
+	if *exclude != "" {
+		re, err := regexp.Compile(*exclude)
+		if err != nil {
+			log.Fatalf("invalid --exclude regex, %v", err)
+		}
+		opts.Exclude = re
+	}
+
+	sourceClient, destClient, err := account.connect()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	if err := migratePrefix(sourceClient, destClient, *sourcePrefix, *destPrefix, opts); err != nil {
+		log.Fatalf("failed to migrate prefix, %v", err)
 	}
 }
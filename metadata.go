@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// sourceMetadata is everything about a source parameter worth preserving on
+// copy beyond its name/value/type/description: tier, allowed pattern,
+// expiration/notification policies and tags. Plain GetParameter/PutParameter
+// drops all of this.
+type sourceMetadata struct {
+	Tier           types.ParameterTier
+	AllowedPattern string
+	Policies       string // raw JSON array, as PutParameterInput.Policies expects
+	Tags           map[string]string
+}
+
+// getSourceMetadata reads the parts of a parameter's metadata that
+// GetParameter alone doesn't return: tier, allowed pattern and policies from
+// DescribeParameters, and tags from ListTagsForResource.
+func getSourceMetadata(client *ssm.Client, name string) (*sourceMetadata, error) {
+	output, err := client.DescribeParameters(context.TODO(), &ssm.DescribeParametersInput{
+		ParameterFilters: []types.ParameterStringFilter{
+			{Key: aws.String("Name"), Values: []string{name}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe %q: %v", name, err)
+	}
+	if len(output.Parameters) == 0 {
+		return nil, fmt.Errorf("parameter %q not found", name)
+	}
+	meta := output.Parameters[0]
+
+	policyTexts := make([]string, 0, len(meta.Policies))
+	for _, p := range meta.Policies {
+		policyTexts = append(policyTexts, aws.ToString(p.PolicyText))
+	}
+	var policies string
+	if len(policyTexts) > 0 {
+		policies = "[" + strings.Join(policyTexts, ",") + "]"
+	}
+
+	tagsOutput, err := client.ListTagsForResource(context.TODO(), &ssm.ListTagsForResourceInput{
+		ResourceType: types.ResourceTypeForTaggingParameter,
+		ResourceId:   aws.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags for %q: %v", name, err)
+	}
+	tags := make(map[string]string, len(tagsOutput.TagList))
+	for _, tag := range tagsOutput.TagList {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return &sourceMetadata{
+		Tier:           meta.Tier,
+		AllowedPattern: aws.ToString(meta.AllowedPattern),
+		Policies:       policies,
+		Tags:           tags,
+	}, nil
+}
+
+// tagDestination applies tags to an existing parameter via AddTagsToResource.
+// PutParameter's own Tags field only takes effect on initial creation, not on
+// an overwrite, so tags on a copy/update always have to go through here.
+func tagDestination(client *ssm.Client, name string, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	tagList := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagList = append(tagList, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	_, err := client.AddTagsToResource(context.TODO(), &ssm.AddTagsToResourceInput{
+		ResourceType: types.ResourceTypeForTaggingParameter,
+		ResourceId:   aws.String(name),
+		Tags:         tagList,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag %q: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package paramstore
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EnvStore is a ParameterStore backed by a dotenv-style file of KEY=VALUE
+// lines. Parameter names are the env var keys (no path hierarchy), so
+// prefix matching in List is a plain string prefix over the key.
+type EnvStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewEnvStore wraps a .env file as a ParameterStore.
+func NewEnvStore(path string) *EnvStore {
+	return &EnvStore{path: path}
+}
+
+func (e *EnvStore) load() (map[string]string, error) {
+	file, err := os.Open(e.path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("env: failed to open %q: %v", e.path, err)
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("env: failed to read %q: %v", e.path, err)
+	}
+	return values, nil
+}
+
+func (e *EnvStore) save(values map[string]string) error {
+	var b strings.Builder
+	for key, value := range values {
+		fmt.Fprintf(&b, "%s=%s\n", key, value)
+	}
+	if err := os.WriteFile(e.path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("env: failed to write %q: %v", e.path, err)
+	}
+	return nil
+}
+
+func (e *EnvStore) Read(ctx context.Context, name string) (*Parameter, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	values, err := e.load()
+	if err != nil {
+		return nil, err
+	}
+	value, ok := values[name]
+	if !ok {
+		return nil, fmt.Errorf("env: key %q not found in %q", name, e.path)
+	}
+	return &Parameter{Name: name, Value: value, Type: "String"}, nil
+}
+
+func (e *EnvStore) List(ctx context.Context, prefix string) ([]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	values, err := e.load()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for key := range values {
+		if strings.HasPrefix(key, prefix) {
+			names = append(names, key)
+		}
+	}
+	return names, nil
+}
+
+func (e *EnvStore) Write(ctx context.Context, param *Parameter) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	values, err := e.load()
+	if err != nil {
+		return err
+	}
+	values[param.Name] = param.Value
+	return e.save(values)
+}
@@ -0,0 +1,115 @@
+package paramstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileStore is a ParameterStore backed by a single local YAML or JSON
+// snapshot file, keyed by parameter name. It exists so a user can dump SSM
+// to a file, edit it, and load it into a different account or backend.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore wraps a YAML (.yaml/.yml) or JSON (.json) snapshot file as a
+// ParameterStore. The file does not need to exist yet; it is created on the
+// first Write.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (f *FileStore) isYAML() bool {
+	ext := strings.ToLower(filepath.Ext(f.path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func (f *FileStore) load() (map[string]Parameter, error) {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return map[string]Parameter{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("file: failed to read %q: %v", f.path, err)
+	}
+
+	params := map[string]Parameter{}
+	if f.isYAML() {
+		err = yaml.Unmarshal(data, &params)
+	} else {
+		err = json.Unmarshal(data, &params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("file: failed to parse %q: %v", f.path, err)
+	}
+	return params, nil
+}
+
+func (f *FileStore) save(params map[string]Parameter) error {
+	var data []byte
+	var err error
+	if f.isYAML() {
+		data, err = yaml.Marshal(params)
+	} else {
+		data, err = json.MarshalIndent(params, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("file: failed to marshal snapshot: %v", err)
+	}
+	if err := os.WriteFile(f.path, data, 0644); err != nil {
+		return fmt.Errorf("file: failed to write %q: %v", f.path, err)
+	}
+	return nil
+}
+
+func (f *FileStore) Read(ctx context.Context, name string) (*Parameter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	params, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	param, ok := params[name]
+	if !ok {
+		return nil, fmt.Errorf("file: parameter %q not found in %q", name, f.path)
+	}
+	return &param, nil
+}
+
+func (f *FileStore) List(ctx context.Context, prefix string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	params, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range params {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (f *FileStore) Write(ctx context.Context, param *Parameter) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	params, err := f.load()
+	if err != nil {
+		return err
+	}
+	params[param.Name] = *param
+	return f.save(params)
+}
@@ -0,0 +1,64 @@
+// Package paramstore provides a small, pluggable abstraction for reading and
+// writing parameters across different backends (AWS SSM Parameter Store,
+// flat YAML/JSON files, .env files, HashiCorp Vault KV v2), so
+// migrate-parameter-store can copy between arbitrary sources and sinks
+// instead of being hardwired to SSM on both ends. It mirrors the
+// provider-per-backend pattern common in Go config libraries such as koanf.
+package paramstore
+
+import "context"
+
+// Parameter is a backend-agnostic parameter value plus metadata: the unit
+// every Source/Sink implementation reads and writes.
+type Parameter struct {
+	Name        string
+	Value       string
+	Type        string // "String", "StringList" or "SecureString"
+	Description string
+	Tier        string
+	KMSKeyID    string
+	Tags        map[string]string
+}
+
+// Source reads parameters from a backend.
+type Source interface {
+	// Read fetches a single parameter by name.
+	Read(ctx context.Context, name string) (*Parameter, error)
+	// List returns every parameter name under prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Sink writes a parameter to a backend.
+type Sink interface {
+	Write(ctx context.Context, param *Parameter) error
+}
+
+// ParameterStore is a backend that can act as both a Source and a Sink, e.g.
+// SSM, which the engine in plan.go/hierarchy.go still talks to directly for
+// its richer dry-run/rollback support.
+type ParameterStore interface {
+	Source
+	Sink
+}
+
+// Copy reads every parameter under prefix from src and writes it to dst,
+// the thin wiring the CLI's `sync` subcommand is built on.
+func Copy(ctx context.Context, src Source, dst Sink, prefix string) (int, error) {
+	names, err := src.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	var copied int
+	for _, name := range names {
+		param, err := src.Read(ctx, name)
+		if err != nil {
+			return copied, err
+		}
+		if err := dst.Write(ctx, param); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+	return copied, nil
+}
@@ -0,0 +1,93 @@
+package paramstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// SSMStore is a ParameterStore backed by AWS Systems Manager Parameter
+// Store.
+type SSMStore struct {
+	client *ssm.Client
+}
+
+// NewSSMStore wraps an existing SSM client as a ParameterStore.
+func NewSSMStore(client *ssm.Client) *SSMStore {
+	return &SSMStore{client: client}
+}
+
+func (s *SSMStore) Read(ctx context.Context, name string) (*Parameter, error) {
+	result, err := s.client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ssm: failed to read %q: %v", name, err)
+	}
+
+	description, err := s.describe(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Parameter{
+		Name:        aws.ToString(result.Parameter.Name),
+		Value:       aws.ToString(result.Parameter.Value),
+		Type:        string(result.Parameter.Type),
+		Description: description,
+	}, nil
+}
+
+func (s *SSMStore) describe(ctx context.Context, name string) (string, error) {
+	output, err := s.client.DescribeParameters(ctx, &ssm.DescribeParametersInput{
+		ParameterFilters: []types.ParameterStringFilter{
+			{Key: aws.String("Name"), Values: []string{name}},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("ssm: failed to describe %q: %v", name, err)
+	}
+	if len(output.Parameters) == 0 {
+		return "", nil
+	}
+	return aws.ToString(output.Parameters[0].Description), nil
+}
+
+func (s *SSMStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	paginator := ssm.NewGetParametersByPathPaginator(s.client, &ssm.GetParametersByPathInput{
+		Path:      aws.String(prefix),
+		Recursive: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ssm: failed to list under %q: %v", prefix, err)
+		}
+		for _, p := range page.Parameters {
+			names = append(names, aws.ToString(p.Name))
+		}
+	}
+	return names, nil
+}
+
+func (s *SSMStore) Write(ctx context.Context, param *Parameter) error {
+	input := &ssm.PutParameterInput{
+		Name:        aws.String(param.Name),
+		Value:       aws.String(param.Value),
+		Type:        types.ParameterType(param.Type),
+		Description: aws.String(param.Description),
+		Overwrite:   aws.Bool(true),
+	}
+	if param.KMSKeyID != "" {
+		input.KeyId = aws.String(param.KMSKeyID)
+	}
+	if _, err := s.client.PutParameter(ctx, input); err != nil {
+		return fmt.Errorf("ssm: failed to write %q: %v", param.Name, err)
+	}
+	return nil
+}
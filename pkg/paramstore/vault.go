@@ -0,0 +1,109 @@
+package paramstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// VaultStore is a ParameterStore backed by a HashiCorp Vault KV v2 secrets
+// engine. Each parameter is stored as a secret with "value", "description"
+// and "type" keys, at mountPath/data/<name>.
+type VaultStore struct {
+	client    *vaultapi.Client
+	mountPath string
+}
+
+// NewVaultStore builds a VaultStore from an already-configured Vault client
+// and the mount path of a KV v2 secrets engine (e.g. "secret").
+func NewVaultStore(client *vaultapi.Client, mountPath string) *VaultStore {
+	return &VaultStore{client: client, mountPath: strings.Trim(mountPath, "/")}
+}
+
+func (v *VaultStore) dataPath(name string) string {
+	return fmt.Sprintf("%s/data/%s", v.mountPath, strings.TrimPrefix(name, "/"))
+}
+
+func (v *VaultStore) metadataPath(prefix string) string {
+	return fmt.Sprintf("%s/metadata/%s", v.mountPath, strings.TrimPrefix(prefix, "/"))
+}
+
+func (v *VaultStore) Read(ctx context.Context, name string) (*Parameter, error) {
+	secret, err := v.client.Logical().ReadWithContext(ctx, v.dataPath(name))
+	if err != nil {
+		return nil, fmt.Errorf("vault: failed to read %q: %v", name, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("vault: parameter %q not found", name)
+	}
+	data, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("vault: unexpected secret shape at %q", name)
+	}
+
+	param := &Parameter{Name: name, Type: "String"}
+	if value, ok := data["value"].(string); ok {
+		param.Value = value
+	}
+	if description, ok := data["description"].(string); ok {
+		param.Description = description
+	}
+	if paramType, ok := data["type"].(string); ok {
+		param.Type = paramType
+	}
+	return param, nil
+}
+
+// List recursively walks every key under prefix, matching the recursive
+// hierarchy semantics the rest of the tool assumes (e.g. migratePrefix's
+// GetParametersByPath). Vault's own KV v2 list is single-level and returns
+// sub-folder keys with a trailing "/" instead of descending into them, so
+// those are recursed into here rather than returned as unreadable names.
+func (v *VaultStore) List(ctx context.Context, prefix string) ([]string, error) {
+	secret, err := v.client.Logical().ListWithContext(ctx, v.metadataPath(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("vault: failed to list %q: %v", prefix, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, nil
+	}
+	keys, ok := secret.Data["keys"].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	base := strings.TrimSuffix(prefix, "/")
+	var names []string
+	for _, k := range keys {
+		key, ok := k.(string)
+		if !ok {
+			continue
+		}
+		if strings.HasSuffix(key, "/") {
+			nested, err := v.List(ctx, base+"/"+key)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, nested...)
+			continue
+		}
+		names = append(names, base+"/"+key)
+	}
+	return names, nil
+}
+
+func (v *VaultStore) Write(ctx context.Context, param *Parameter) error {
+	_, err := v.client.Logical().WriteWithContext(ctx, v.dataPath(param.Name), map[string]interface{}{
+		"data": map[string]interface{}{
+			"value":       param.Value,
+			"description": param.Description,
+			"type":        param.Type,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("vault: failed to write %q: %v", param.Name, err)
+	}
+	return nil
+}
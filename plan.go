@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ParameterChange is one resolved source->destination copy that a plan would
+// carry out. It never holds the raw value, only a length and hash, so a
+// dry-run diff can be printed (and a plan file saved) without leaking secrets.
+type ParameterChange struct {
+	SourceName    string              `json:"source_name"`
+	DestName      string              `json:"dest_name"`
+	Action        string              `json:"action"` // "create", "update", "skip" or "skip-no-overwrite"
+	Type          types.ParameterType `json:"type"`
+	ValueLength   int                 `json:"value_length"`
+	ValueHash     string              `json:"value_hash"`
+	Description   string              `json:"description"`
+	DestExists    bool                `json:"dest_exists"`
+	DestValueHash string              `json:"dest_value_hash,omitempty"`
+	Tags          map[string]string   `json:"tags,omitempty"`
+	KMSKeyID      string              `json:"kms_key_id,omitempty"`
+}
+
+// MigrationPlan is the fully resolved set of changes a migration config
+// would apply, suitable for printing as a dry-run diff or persisting with
+// --plan-file for a later `apply`.
+type MigrationPlan struct {
+	GeneratedAt string            `json:"generated_at"`
+	Changes     []ParameterChange `json:"changes"`
+}
+
+func hashValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// planJob is one source parameter a rule resolved, paired with the
+// destination name it maps onto.
+type planJob struct {
+	rule       MigrationRule
+	env        string
+	sourceName string
+	destName   string
+}
+
+// resolvePlan walks every rule/environment/parameter in cfg, reads the
+// current state of both the source and (if present) the destination
+// parameter, and returns the plan of changes without writing anything.
+// sourceClient and destClient may be the same client (single-account
+// migration) or two different ones (cross-account/cross-region), each
+// built with its own profile/region/assumed role by the caller.
+//
+// Parameter state is read with ssm.GetParameters/DescribeParameters batched
+// to 10 names per call and fanned out across a bounded worker pool
+// (concurrency, default defaultConcurrency), rate-limited and retried with
+// backoff on throttling, instead of one API call per parameter.
+func resolvePlan(sourceClient, destClient *ssm.Client, cfg *MigrationConfig, concurrency int) (*MigrationPlan, error) {
+	ctx := context.TODO()
+	limiter := newRateLimiter(concurrency * 2)
+	defer limiter.close()
+
+	var jobs []planJob
+	for _, rule := range cfg.Rules {
+		for _, env := range rule.Environments {
+			names, err := namesForRule(sourceClient, rule, env)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q (%s): %v", rule.Name, env, err)
+			}
+			basePath := resolvedPath(rule.SourcePath, env)
+			destBasePath := resolvedPath(rule.DestPath, env)
+			for _, name := range names {
+				jobs = append(jobs, planJob{
+					rule:       rule,
+					env:        env,
+					sourceName: name,
+					destName:   destBasePath + name[len(basePath):],
+				})
+			}
+		}
+	}
+
+	sourceNames := make([]string, len(jobs))
+	destNames := make([]string, len(jobs))
+	for i, job := range jobs {
+		sourceNames[i] = job.sourceName
+		destNames[i] = job.destName
+	}
+
+	sourceParams, err := getParametersBatch(ctx, sourceClient, limiter, concurrency, sourceNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to batch-read source parameters: %v", err)
+	}
+	sourceDescriptions, err := describeParametersBatch(ctx, sourceClient, limiter, concurrency, sourceNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to batch-describe source parameters: %v", err)
+	}
+	destParams, err := getParametersBatch(ctx, destClient, limiter, concurrency, destNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to batch-read destination parameters: %v", err)
+	}
+
+	plan := &MigrationPlan{GeneratedAt: time.Now().UTC().Format(time.RFC3339)}
+	for i, job := range jobs {
+		source, ok := sourceParams[job.sourceName]
+		if !ok {
+			return nil, fmt.Errorf("rule %q (%s): source parameter %q not found", job.rule.Name, job.env, job.sourceName)
+		}
+		change := buildChange(job, source, sourceDescriptions[job.sourceName], destParams[job.destName])
+		plan.Changes = append(plan.Changes, change)
+		fmt.Printf("resolved %d/%d: %s -> %s (%s)\n", i+1, len(jobs), job.sourceName, job.destName, change.Action)
+	}
+	return plan, nil
+}
+
+// namesForRule returns the source parameter names a rule selects for one
+// environment: the explicit Parameters list, or everything found under
+// SourcePath/Prefix via DescribeParameters, narrowed further by Glob (matched
+// against each name's suffix after SourcePath/Prefix) when set.
+func namesForRule(client *ssm.Client, rule MigrationRule, env string) ([]string, error) {
+	basePath := resolvedPath(rule.SourcePath, env)
+
+	if len(rule.Parameters) > 0 {
+		names := make([]string, len(rule.Parameters))
+		for i, p := range rule.Parameters {
+			names[i] = basePath + p
+		}
+		return names, nil
+	}
+
+	filterValue := basePath
+	if rule.Prefix != "" {
+		filterValue = basePath + rule.Prefix
+	}
+	input := &ssm.DescribeParametersInput{
+		ParameterFilters: []types.ParameterStringFilter{
+			{
+				Key:    aws.String("Name"),
+				Option: aws.String("BeginsWith"),
+				Values: []string{filterValue},
+			},
+		},
+	}
+	var names []string
+	paginator := ssm.NewDescribeParametersPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list parameters under %q: %v", filterValue, err)
+		}
+		for _, p := range page.Parameters {
+			names = append(names, aws.ToString(p.Name))
+		}
+	}
+
+	if rule.Glob == "" {
+		return names, nil
+	}
+	matched := names[:0]
+	for _, name := range names {
+		suffix := strings.TrimPrefix(name, filterValue)
+		ok, err := path.Match(rule.Glob, suffix)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid glob %q: %v", rule.Name, rule.Glob, err)
+		}
+		if ok {
+			matched = append(matched, name)
+		}
+	}
+	return matched, nil
+}
+
+// buildChange turns one resolved source parameter (plus, if any, the
+// current destination parameter) into a plan entry. dest is the zero value
+// when the destination does not exist yet.
+func buildChange(job planJob, source types.Parameter, description string, dest types.Parameter) ParameterChange {
+	change := ParameterChange{
+		SourceName:  job.sourceName,
+		DestName:    job.destName,
+		Type:        source.Type,
+		ValueLength: len(aws.ToString(source.Value)),
+		ValueHash:   hashValue(aws.ToString(source.Value)),
+		Description: description,
+		Tags:        job.rule.Tags,
+		KMSKeyID:    job.rule.KMSKeyID,
+		Action:      "create",
+	}
+
+	if dest.Value != nil {
+		change.DestExists = true
+		change.DestValueHash = hashValue(aws.ToString(dest.Value))
+		switch {
+		case change.DestValueHash == change.ValueHash:
+			change.Action = "skip"
+		case job.rule.Overwrite:
+			change.Action = "update"
+		default:
+			// Differs from the source but the rule doesn't allow overwriting
+			// it; distinct from "skip" so the dry-run diff doesn't report a
+			// genuine drift as "unchanged".
+			change.Action = "skip-no-overwrite"
+		}
+	}
+
+	return change
+}
+
+// printDiff renders a plan as a unified-diff-style summary: one line per
+// change showing what would happen, without ever printing the real values.
+func printDiff(plan *MigrationPlan) {
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case "skip":
+			fmt.Printf("  %s -> %s (unchanged, skipped)\n", c.SourceName, c.DestName)
+		case "skip-no-overwrite":
+			fmt.Printf("! %s -> %s (differs, hash=%s vs dest hash=%s, skipped: --overwrite not set)\n",
+				c.SourceName, c.DestName, c.ValueHash, c.DestValueHash)
+		case "update":
+			fmt.Printf("~ %s -> %s (type=%s len=%d hash=%s, replaces hash=%s)\n",
+				c.SourceName, c.DestName, c.Type, c.ValueLength, c.ValueHash, c.DestValueHash)
+		default:
+			fmt.Printf("+ %s -> %s (type=%s len=%d hash=%s)\n",
+				c.SourceName, c.DestName, c.Type, c.ValueLength, c.ValueHash)
+		}
+	}
+	fmt.Printf("\n%d change(s) planned\n", countApplicable(plan))
+}
+
+// isSkipped reports whether a change's action means apply has nothing to do:
+// either it's genuinely unchanged ("skip"), or it differs but the rule
+// doesn't allow overwriting it ("skip-no-overwrite").
+func isSkipped(action string) bool {
+	return action == "skip" || action == "skip-no-overwrite"
+}
+
+func countApplicable(plan *MigrationPlan) int {
+	n := 0
+	for _, c := range plan.Changes {
+		if !isSkipped(c.Action) {
+			n++
+		}
+	}
+	return n
+}
+
+// savePlanFile persists a resolved plan to disk so a later `apply` run can
+// execute exactly what was reviewed in the dry-run.
+func savePlanFile(plan *MigrationPlan, path string) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write plan file %q: %v", path, err)
+	}
+	return nil
+}
+
+// loadPlanFile reads back a plan previously written by savePlanFile.
+func loadPlanFile(path string) (*MigrationPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file %q: %v", path, err)
+	}
+	var plan MigrationPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file %q: %v", path, err)
+	}
+	return &plan, nil
+}
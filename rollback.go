@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// RollbackEntry records what the destination parameter looked like (if
+// anything) before apply wrote to it, so a rollback can restore or delete it.
+// It deliberately holds a reference to the prior version rather than its
+// value: the journal is written to disk, and for a SecureString that value
+// is decrypted plaintext, so storing it directly would put production
+// secrets in a cleartext file. rollbackJournal re-reads PriorVersion from
+// SSM (GetParameter's "name:version" syntax) at restore time instead.
+type RollbackEntry struct {
+	DestName     string              `json:"dest_name"`
+	Existed      bool                `json:"existed"`
+	PriorType    types.ParameterType `json:"prior_type,omitempty"`
+	PriorVersion int64               `json:"prior_version,omitempty"`
+}
+
+// RollbackJournal is the record of a single `apply` run, written as each
+// parameter is touched so a partially-applied migration can still be undone.
+type RollbackJournal struct {
+	PlanFile string          `json:"plan_file"`
+	Entries  []RollbackEntry `json:"entries"`
+}
+
+func loadRollbackJournal(path string) (*RollbackJournal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollback journal %q: %v", path, err)
+	}
+	var journal RollbackJournal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("failed to parse rollback journal %q: %v", path, err)
+	}
+	return &journal, nil
+}
+
+func (j *RollbackJournal) save(path string) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rollback journal: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write rollback journal %q: %v", path, err)
+	}
+	return nil
+}
+
+// applyPlan executes every non-skipped change in plan, writing a rollback
+// journal entry for each destination parameter before it is touched. Source
+// values are batch-read once up front (ssm.GetParameters, chunked and
+// concurrent) rather than one GetParameter call per change; writes stay
+// sequential so the journal is always a faithful, ordered undo log.
+// sourceClient and destClient may be the same client or, for a cross-account/
+// cross-region migration, two different ones. When includeHistory is set,
+// every prior version of each source parameter is replayed onto the
+// destination via GetParameterHistory instead of writing only its current
+// value.
+func applyPlan(sourceClient, destClient *ssm.Client, plan *MigrationPlan, planFile, journalPath string, concurrency int, includeHistory bool) error {
+	applicable := make([]string, 0, len(plan.Changes))
+	destOf := make(map[string]string, len(plan.Changes))
+	for _, change := range plan.Changes {
+		if isSkipped(change.Action) {
+			continue
+		}
+		applicable = append(applicable, change.SourceName)
+		destOf[change.DestName] = change.DestName
+	}
+
+	ctx := context.TODO()
+	limiter := newRateLimiter(concurrency * 2)
+	defer limiter.close()
+
+	sources, err := getParametersBatch(ctx, sourceClient, limiter, concurrency, applicable)
+	if err != nil {
+		return fmt.Errorf("failed to batch-read source parameters: %v", err)
+	}
+	dests, err := getParametersBatch(ctx, destClient, limiter, concurrency, destValues(destOf))
+	if err != nil {
+		return fmt.Errorf("failed to batch-read destination parameters: %v", err)
+	}
+
+	journal := &RollbackJournal{PlanFile: planFile}
+
+	for _, change := range plan.Changes {
+		if isSkipped(change.Action) {
+			continue
+		}
+
+		entry := RollbackEntry{DestName: change.DestName}
+		if prior, ok := dests[change.DestName]; ok {
+			entry.Existed = true
+			entry.PriorType = prior.Type
+			entry.PriorVersion = prior.Version
+		}
+		journal.Entries = append(journal.Entries, entry)
+
+		// Persist the journal before writing so a crash mid-apply still
+		// leaves a record of everything committed so far.
+		if err := journal.save(journalPath); err != nil {
+			return err
+		}
+
+		source, ok := sources[change.SourceName]
+		if !ok {
+			return fmt.Errorf("source parameter %q not found", change.SourceName)
+		}
+		if includeHistory {
+			if err := replayHistory(sourceClient, destClient, change.SourceName, change.DestName, change.KMSKeyID); err != nil {
+				return fmt.Errorf("failed to apply change %s -> %s: %v", change.SourceName, change.DestName, err)
+			}
+		}
+		// Even under --include-history, the final write always goes through
+		// putParameter so tier/allowed-pattern/policies/tags are applied to
+		// the destination's current version; replayHistory alone only
+		// carries what GetParameterHistory returns per version, which
+		// doesn't include tags and isn't guaranteed to include tier/policies
+		// on every historical entry.
+		overwrite := change.Action == "update" || includeHistory
+		if err := putParameter(sourceClient, destClient, change.SourceName, change.DestName, change.Description, &source, overwrite, change.KMSKeyID, change.Tags); err != nil {
+			return fmt.Errorf("failed to apply change %s -> %s: %v", change.SourceName, change.DestName, err)
+		}
+		fmt.Printf("applied %s -> %s\n", change.SourceName, change.DestName)
+	}
+
+	return nil
+}
+
+func destValues(destOf map[string]string) []string {
+	dests := make([]string, 0, len(destOf))
+	for _, dest := range destOf {
+		dests = append(dests, dest)
+	}
+	return dests
+}
+
+// rollbackJournal restores every destination parameter in journal to its
+// pre-apply state: prior value if it existed, otherwise deleted. It only
+// ever touches the destination account/region the apply wrote to. The prior
+// value itself is never persisted in the journal (see RollbackEntry); it's
+// re-read from SSM's own version history by name:version just before it's
+// written back.
+func rollbackJournal(destClient *ssm.Client, journal *RollbackJournal) error {
+	for _, entry := range journal.Entries {
+		if entry.Existed {
+			versioned := fmt.Sprintf("%s:%d", entry.DestName, entry.PriorVersion)
+			prior, err := destClient.GetParameter(context.TODO(), &ssm.GetParameterInput{
+				Name:           aws.String(versioned),
+				WithDecryption: aws.Bool(true),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to read prior version %q: %v", versioned, err)
+			}
+
+			input := &ssm.PutParameterInput{
+				Name:      aws.String(entry.DestName),
+				Value:     prior.Parameter.Value,
+				Type:      entry.PriorType,
+				Overwrite: aws.Bool(true),
+			}
+			if _, err := destClient.PutParameter(context.TODO(), input); err != nil {
+				return fmt.Errorf("failed to restore %q: %v", entry.DestName, err)
+			}
+			fmt.Printf("restored %s to version %d\n", entry.DestName, entry.PriorVersion)
+			continue
+		}
+
+		input := &ssm.DeleteParameterInput{Name: aws.String(entry.DestName)}
+		if _, err := destClient.DeleteParameter(context.TODO(), input); err != nil {
+			return fmt.Errorf("failed to delete %q: %v", entry.DestName, err)
+		}
+		fmt.Printf("deleted %s (did not exist before apply)\n", entry.DestName)
+	}
+	return nil
+}
+
+func mustLoadJournalForRollback(path string) *RollbackJournal {
+	journal, err := loadRollbackJournal(path)
+	if err != nil {
+		log.Fatalf("failed to load rollback journal, %v", err)
+	}
+	return journal
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"github.com/frankymfv/migrate-parameter-store/pkg/paramstore"
+)
+
+// syncFlagSet holds the parsed flags for the `sync` subcommand.
+type syncFlagSet struct {
+	sourceType, sourceArg, sourceMount, sourceProfile, sourceRegion, sourceRoleARN *string
+	destType, destArg, destMount, destProfile, destRegion, destRoleARN             *string
+	prefix                                                                         *string
+}
+
+func flagSetForSync(args []string) *syncFlagSet {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	sf := &syncFlagSet{
+		sourceType:    fs.String("source-type", "", "source backend: ssm, file, env or vault"),
+		sourceArg:     fs.String("source-arg", "", "source backend argument (profile name, file path or Vault address)"),
+		sourceMount:   fs.String("source-vault-mount", "secret", "source Vault KV v2 mount path"),
+		sourceProfile: fs.String("source-profile", "", "alias for --source-arg when source-type=ssm"),
+		sourceRegion:  fs.String("source-region", "", "AWS region for the source when source-type=ssm"),
+		sourceRoleARN: fs.String("source-role-arn", "", "if set, assume this role on top of --source-profile"),
+		destType:      fs.String("dest-type", "", "destination backend: ssm, file, env or vault"),
+		destArg:       fs.String("dest-arg", "", "destination backend argument (profile name, file path or Vault address)"),
+		destMount:     fs.String("dest-vault-mount", "secret", "destination Vault KV v2 mount path"),
+		destProfile:   fs.String("dest-profile", "", "alias for --dest-arg when dest-type=ssm"),
+		destRegion:    fs.String("dest-region", "", "AWS region for the destination when dest-type=ssm"),
+		destRoleARN:   fs.String("dest-role-arn", "", "if set, assume this role on top of --dest-profile"),
+		prefix:        fs.String("prefix", "", "parameter name prefix to copy"),
+	}
+	fs.Parse(args)
+
+	if *sf.sourceProfile != "" {
+		*sf.sourceArg = *sf.sourceProfile
+	}
+	if *sf.destProfile != "" {
+		*sf.destArg = *sf.destProfile
+	}
+	if *sf.sourceType == "" || *sf.destType == "" {
+		log.Fatalf("sync requires --source-type and --dest-type")
+	}
+	return sf
+}
+
+// storeFlags describes the flags needed to build one paramstore.Source or
+// paramstore.Sink: --<role>-type selects the backend and --<role>-arg is
+// interpreted according to that backend (an SSM profile, a file path, a
+// Vault address, ...).
+type storeFlags struct {
+	kind    string // "ssm", "file", "env" or "vault"
+	arg     string
+	mount   string // vault only
+	profile string // ssm only
+	region  string // ssm only
+	roleARN string // ssm only
+}
+
+// buildStore turns a storeFlags into a concrete paramstore.ParameterStore,
+// the CLI's wiring of a Source/Sink pair described in pkg/paramstore.
+func buildStore(flags storeFlags) (paramstore.ParameterStore, error) {
+	switch flags.kind {
+	case "ssm":
+		client, err := connectToAWS(flags.profile, flags.region, flags.roleARN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to AWS, %v", err)
+		}
+		return paramstore.NewSSMStore(client), nil
+	case "file":
+		return paramstore.NewFileStore(flags.arg), nil
+	case "env":
+		return paramstore.NewEnvStore(flags.arg), nil
+	case "vault":
+		cfg := vaultapi.DefaultConfig()
+		cfg.Address = flags.arg
+		client, err := vaultapi.NewClient(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build Vault client, %v", err)
+		}
+		return paramstore.NewVaultStore(client, flags.mount), nil
+	default:
+		return nil, fmt.Errorf("unknown store type %q (want ssm, file, env or vault)", flags.kind)
+	}
+}
+
+func runSync(args []string) {
+	fs := flagSetForSync(args)
+
+	source, err := buildStore(storeFlags{
+		kind:    *fs.sourceType,
+		arg:     *fs.sourceArg,
+		mount:   *fs.sourceMount,
+		profile: *fs.sourceArg,
+		region:  *fs.sourceRegion,
+		roleARN: *fs.sourceRoleARN,
+	})
+	if err != nil {
+		log.Fatalf("failed to build source, %v", err)
+	}
+	dest, err := buildStore(storeFlags{
+		kind:    *fs.destType,
+		arg:     *fs.destArg,
+		mount:   *fs.destMount,
+		profile: *fs.destArg,
+		region:  *fs.destRegion,
+		roleARN: *fs.destRoleARN,
+	})
+	if err != nil {
+		log.Fatalf("failed to build destination, %v", err)
+	}
+
+	copied, err := paramstore.Copy(context.TODO(), source, dest, *fs.prefix)
+	if err != nil {
+		log.Fatalf("sync failed after copying %d parameter(s), %v", copied, err)
+	}
+	fmt.Printf("synced %d parameter(s) under %q\n", copied, *fs.prefix)
+}